@@ -0,0 +1,10 @@
+package botfaces
+
+// Concurrency lets a bot control how many monitors the engine updates in
+// parallel during a single tick. Bots that do not implement this get a
+// small default worker pool.
+type Concurrency interface {
+	// Concurrency returns the maximum number of monitor updates the
+	// engine may run at once.
+	Concurrency() int
+}
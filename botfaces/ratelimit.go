@@ -0,0 +1,58 @@
+package botfaces
+
+import "time"
+
+const (
+	// lowBudget is the remaining-request threshold at or below which
+	// BlockTime stops spreading requests out and instead waits for the
+	// window to reset entirely.
+	lowBudget = 2
+	// minInterval is the shortest wait BlockTime returns while spreading
+	// requests across the window. Waiting out the window on a low budget
+	// returns Reset as it is, which may be shorter.
+	minInterval = time.Second
+)
+
+// RateLimit is a snapshot of Reddit's API rate-limit budget, parsed from
+// the X-Ratelimit-Used, X-Ratelimit-Remaining and X-Ratelimit-Reset headers
+// Reddit attaches to every response. It lives in botfaces, rather than
+// internal/client, so a bot implementing RateLimitObserver can actually
+// name the type.
+type RateLimit struct {
+	// Used is the number of requests used in the current window.
+	Used float64
+	// Remaining is the number of requests left in the current window.
+	Remaining float64
+	// Reset is how long until the current window resets.
+	Reset time.Duration
+	// Observed is false until a response carrying rate-limit headers has
+	// been seen.
+	Observed bool
+}
+
+// BlockTime returns how long to wait before the next request so remaining
+// requests are spread evenly across the rest of the window. If the budget
+// is nearly exhausted, it waits out the whole window instead. It returns 0
+// if no rate limit has been observed yet, leaving the decision to the
+// caller's own fallback.
+func (l RateLimit) BlockTime() time.Duration {
+	if !l.Observed {
+		return 0
+	}
+	if l.Remaining <= lowBudget {
+		return l.Reset
+	}
+	if interval := time.Duration(float64(l.Reset) / l.Remaining); interval > minInterval {
+		return interval
+	}
+	return minInterval
+}
+
+// RateLimitObserver lets a bot react to Reddit's rate-limit budget. If
+// implemented, the engine calls RateLimit with the most recently observed
+// budget each time it computes how long to block before the next tick. It
+// is not called for bots that also implement BlockTimer, nor while the
+// engine has no client.
+type RateLimitObserver interface {
+	RateLimit(limit RateLimit)
+}
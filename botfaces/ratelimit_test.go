@@ -0,0 +1,46 @@
+package botfaces
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimitBlockTime(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit RateLimit
+		want  time.Duration
+	}{
+		{
+			name:  "unobserved",
+			limit: RateLimit{},
+			want:  0,
+		},
+		{
+			name:  "spreads requests across the window",
+			limit: RateLimit{Remaining: 300, Reset: 600 * time.Second, Observed: true},
+			want:  2 * time.Second,
+		},
+		{
+			name:  "floors at minInterval",
+			limit: RateLimit{Remaining: 700, Reset: 600 * time.Second, Observed: true},
+			want:  minInterval,
+		},
+		{
+			name:  "waits out the window when budget is nearly gone",
+			limit: RateLimit{Remaining: 1, Reset: 45 * time.Second, Observed: true},
+			want:  45 * time.Second,
+		},
+		{
+			name:  "waits out the window when budget is fully exhausted",
+			limit: RateLimit{Remaining: 0, Reset: 60 * time.Second, Observed: true},
+			want:  60 * time.Second,
+		},
+	}
+
+	for _, test := range tests {
+		if got := test.limit.BlockTime(); got != test.want {
+			t.Errorf("%s: BlockTime() = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
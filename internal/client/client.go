@@ -0,0 +1,29 @@
+// Package client issues authenticated HTTP requests to Reddit's API.
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// Logger is the structured logging interface of this package. It has the
+// same shape as engine.Logger, which client cannot import without creating
+// an import cycle; any engine.Logger satisfies this interface. No Client in
+// this package, including the one returned by Retrying, logs to it.
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+}
+
+// Client sends requests to Reddit on behalf of a bot.
+type Client interface {
+	// Do sends req and returns its response. Implementations must abort
+	// the in-flight request and return promptly when ctx is cancelled,
+	// and must record the response's X-Ratelimit-* headers so RateLimit
+	// reflects them.
+	Do(ctx context.Context, req *http.Request) (*http.Response, error)
+	// RateLimit returns the most recently observed rate-limit budget.
+	RateLimit() RateLimit
+}
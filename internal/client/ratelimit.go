@@ -0,0 +1,49 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/turnage/graw/botfaces"
+)
+
+// RateLimit is an alias for botfaces.RateLimit, so this package and the
+// bots that implement botfaces.RateLimitObserver share one type.
+type RateLimit = botfaces.RateLimit
+
+// RateLimitTracker maintains the most recently observed RateLimit budget.
+// It is safe for concurrent use.
+type RateLimitTracker struct {
+	mu    sync.Mutex
+	limit RateLimit
+}
+
+// Update parses the X-Ratelimit-* headers from header and records them as
+// the current budget. Responses without all three headers leave the
+// tracker unchanged.
+func (t *RateLimitTracker) Update(header http.Header) {
+	used, uerr := strconv.ParseFloat(header.Get("X-Ratelimit-Used"), 64)
+	remaining, rerr := strconv.ParseFloat(header.Get("X-Ratelimit-Remaining"), 64)
+	resetSeconds, serr := strconv.ParseFloat(header.Get("X-Ratelimit-Reset"), 64)
+	if uerr != nil || rerr != nil || serr != nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.limit = RateLimit{
+		Used:      used,
+		Remaining: remaining,
+		Reset:     time.Duration(resetSeconds * float64(time.Second)),
+		Observed:  true,
+	}
+}
+
+// Current returns the most recently observed rate-limit budget.
+func (t *RateLimitTracker) Current() RateLimit {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.limit
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimitTrackerUpdate(t *testing.T) {
+	var tracker RateLimitTracker
+
+	header := http.Header{}
+	header.Set("X-Ratelimit-Used", "10")
+	header.Set("X-Ratelimit-Remaining", "590")
+	header.Set("X-Ratelimit-Reset", "600")
+	tracker.Update(header)
+
+	got := tracker.Current()
+	want := RateLimit{Used: 10, Remaining: 590, Reset: 600 * time.Second, Observed: true}
+	if got != want {
+		t.Fatalf("Current() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRateLimitTrackerIgnoresIncompleteHeaders(t *testing.T) {
+	var tracker RateLimitTracker
+	tracker.Update(http.Header{})
+
+	if got := tracker.Current(); got.Observed {
+		t.Fatalf("Current() = %+v, want Observed == false", got)
+	}
+}
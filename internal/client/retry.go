@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy controls how a retrying Client handles transient failures.
+type RetryPolicy struct {
+	// MaxRetries is the most attempts Do will make beyond the first.
+	MaxRetries int
+	// BaseDelay is the backoff before the first retry; each subsequent
+	// retry doubles it (capped at MaxDelay). Jitter then picks the actual
+	// wait at random between half and all of that backoff.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff between retries.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is a conservative policy suitable for most bots.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 5,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   30 * time.Second,
+}
+
+// delay returns the jittered backoff before retry attempt (1-indexed).
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := p.BaseDelay << uint(attempt-1)
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// drainAndClose discards resp's body and closes it, so the underlying
+// connection can be reused for a retry instead of leaking.
+func drainAndClose(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+// transient reports whether a response/error pair from Do is worth
+// retrying: network errors, 5xx, 429, and context.DeadlineExceeded. Other
+// 4xx responses (bad auth, not found, ...) are permanent failures.
+func transient(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// Authenticator refreshes the credentials a Client uses, so a retrying
+// Client can recover from an expired OAuth token instead of retrying the
+// same 401 until it runs out of attempts.
+type Authenticator interface {
+	// Refresh obtains a new token. Implementations should update whatever
+	// the wrapped Client uses to authenticate its requests.
+	Refresh(ctx context.Context) error
+}
+
+// Retrying wraps cli so Do retries transient failures with jittered
+// exponential backoff up to policy's cap, refreshing auth via authenticate
+// (if non-nil) whenever a request comes back 401. Permanent failures are
+// returned on the first attempt. When retries are exhausted, the returned
+// error is the one the caller (typically a single monitor's Update) should
+// surface to botfaces.Failer; it does not affect any other monitor.
+func Retrying(cli Client, policy RetryPolicy, authenticate Authenticator) Client {
+	return &retrier{cli: cli, policy: policy, auth: authenticate}
+}
+
+type retrier struct {
+	cli    Client
+	policy RetryPolicy
+	auth   Authenticator
+}
+
+func (r *retrier) RateLimit() RateLimit {
+	return r.cli.RateLimit()
+}
+
+func (r *retrier) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	var resp *http.Response
+	var err error
+
+	for attempt := 0; attempt <= r.policy.MaxRetries; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, gerr := req.GetBody()
+			if gerr != nil {
+				return nil, gerr
+			}
+			req.Body = body
+		}
+
+		resp, err = r.cli.Do(ctx, req)
+
+		if err == nil && resp.StatusCode == http.StatusUnauthorized && r.auth != nil {
+			drainAndClose(resp)
+			if aerr := r.auth.Refresh(ctx); aerr != nil {
+				return nil, fmt.Errorf("graw: refreshing auth after 401: %w", aerr)
+			}
+			continue
+		}
+		if !transient(resp, err) {
+			return resp, err
+		}
+		if attempt == r.policy.MaxRetries {
+			break
+		}
+		drainAndClose(resp)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(r.policy.delay(attempt + 1)):
+		}
+	}
+
+	if err != nil {
+		return resp, fmt.Errorf("graw: giving up after %d retries: %w", r.policy.MaxRetries, err)
+	}
+	return resp, fmt.Errorf("graw: giving up after %d retries: unexpected status %s", r.policy.MaxRetries, resp.Status)
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeClient struct {
+	http *http.Client
+	rl   RateLimitTracker
+}
+
+func (f *fakeClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	resp, err := f.http.Do(req.WithContext(ctx))
+	if err == nil {
+		f.rl.Update(resp.Header)
+	}
+	return resp, err
+}
+
+func (f *fakeClient) RateLimit() RateLimit {
+	return f.rl.Current()
+}
+
+func testPolicy() RetryPolicy {
+	return RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+}
+
+func TestRetryingRecoversFromFlaky500s(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cli := Retrying(&fakeClient{http: srv.Client()}, testPolicy(), nil)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := cli.Do(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Do() error = %v, want nil once the server recovers", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Do() status = %d, want 200", resp.StatusCode)
+	}
+	if hits != 3 {
+		t.Fatalf("handler hit %d times, want 3", hits)
+	}
+}
+
+type fakeAuthenticator struct {
+	refreshed int32
+}
+
+func (a *fakeAuthenticator) Refresh(ctx context.Context) error {
+	atomic.AddInt32(&a.refreshed, 1)
+	return nil
+}
+
+func TestRetryingRefreshesAuthOn401(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	auth := &fakeAuthenticator{}
+	cli := Retrying(&fakeClient{http: srv.Client()}, testPolicy(), auth)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := cli.Do(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Do() error = %v, want nil after refreshing auth", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Do() status = %d, want 200", resp.StatusCode)
+	}
+	if got := atomic.LoadInt32(&auth.refreshed); got != 1 {
+		t.Fatalf("Refresh called %d times, want 1", got)
+	}
+	if hits != 2 {
+		t.Fatalf("handler hit %d times, want 2 (one 401, one retry)", hits)
+	}
+}
+
+func TestRetryingGivesUpOnPermanentError(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	cli := Retrying(&fakeClient{http: srv.Client()}, testPolicy(), nil)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := cli.Do(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Do() error = %v, want nil (a 4xx is not an error returned by Do)", err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("Do() status = %d, want 404", resp.StatusCode)
+	}
+	if hits != 1 {
+		t.Fatalf("handler hit %d times, want 1 (permanent errors must not be retried)", hits)
+	}
+}
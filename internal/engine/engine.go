@@ -3,7 +3,10 @@ package engine
 
 import (
 	"container/list"
+	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,67 +21,98 @@ const (
 	// blockTime is the amount of time to block between letting the next
 	// monitor update.
 	defaultBlockTime = time.Minute / 30
+	// defaultConcurrency is the number of monitors updated in parallel
+	// when the bot does not specify its own via botfaces.Concurrency.
+	defaultConcurrency = 4
 )
 
+// multiError aggregates the independent errors from a single tick of
+// concurrent monitor updates.
+type multiError []error
+
+func (m multiError) Error() string {
+	msgs := make([]string, len(m))
+	for i, err := range m {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
 type Engine struct {
 	// cli is the http client this engine uses to communicate with Reddit.
 	cli client.Client
 	// bot is the bot this engine runs.
 	bot interface{}
-	// stopSig is a channel over which bots can send a signal to the engine
-	// to stop.
-	stopSig chan bool
-	// stop is a flag for the engine to conclude its main loop.
-	stop bool
+	// log receives structured diagnostics from the engine. Use logger()
+	// to read it, since a zero-value Engine leaves it nil.
+	log Logger
 	// Mutex protects all fields below.
 	sync.Mutex
+	// cancel stops the context RunContext is currently running under, if
+	// any. Stop calls it to begin a graceful shutdown.
+	cancel context.CancelFunc
 	// monitors is a list of the monitors this engine uses to get events
 	// from Reddit.
 	monitors *list.List
 	// userMonitors is a map of username to the monitors dedicated to that
 	// username.
 	userMonitors map[string]*list.Element
+	// events fans monitor output out to Subscribe callers, in addition to
+	// the bot-callback dispatch below. Use eventBus() to read it.
+	events *eventBus
 }
 
-// Reply submits a reply.
-func (e *Engine) Reply(parentName, text string) error {
-	return api.Reply(e.cli.Do, parentName, text)
+// Reply submits a reply. ctx cancels the underlying HTTP request.
+func (e *Engine) Reply(ctx context.Context, parentName, text string) error {
+	return api.Reply(ctx, e.cli.Do, parentName, text)
 }
 
-// SendMessage sends a private message.
-func (e *Engine) SendMessage(user, subject, text string) error {
-	return api.Compose(e.cli.Do, user, subject, text)
+// SendMessage sends a private message. ctx cancels the underlying HTTP
+// request.
+func (e *Engine) SendMessage(ctx context.Context, user, subject, text string) error {
+	return api.Compose(ctx, e.cli.Do, user, subject, text)
 }
 
-// SelfPost makes a self (text) post to a subreddit.
-func (e *Engine) SelfPost(subreddit, title, text string) error {
-	return api.Submit(e.cli.Do, subreddit, "self", title, text)
+// SelfPost makes a self (text) post to a subreddit. ctx cancels the
+// underlying HTTP request.
+func (e *Engine) SelfPost(ctx context.Context, subreddit, title, text string) error {
+	return api.Submit(ctx, e.cli.Do, subreddit, "self", title, text)
 }
 
-// LinkPost makes a link post to a subreddit.
-func (e *Engine) LinkPost(subreddit, title, url string) error {
-	return api.Submit(e.cli.Do, subreddit, "link", title, url)
+// LinkPost makes a link post to a subreddit. ctx cancels the underlying
+// HTTP request.
+func (e *Engine) LinkPost(ctx context.Context, subreddit, title, url string) error {
+	return api.Submit(ctx, e.cli.Do, subreddit, "link", title, url)
 }
 
-// WatchUser starts monitoring a user.
-func (e *Engine) WatchUser(user string) error {
+// WatchUser starts monitoring a user. ctx governs only the setup of the
+// monitor; once running, each tick's update is governed by the context
+// passed to RunContext.
+func (e *Engine) WatchUser(ctx context.Context, user string) error {
 	han, ok := e.bot.(botfaces.UserHandler)
 	if !ok {
 		return fmt.Errorf("bot cannot handle user posts or comments")
 	}
 
 	mon, err := monitor.UserMonitor(
-		func(path, tip string, limit int) (
+		func(ctx context.Context, path, tip string, limit int) (
 			[]*redditproto.Link,
 			[]*redditproto.Comment,
 			[]*redditproto.Message,
 			error,
 		) {
-			return api.Scrape(e.cli.Do, path, tip, limit)
+			return api.Scrape(ctx, e.cli.Do, path, tip, limit)
+		},
+		func(post *redditproto.Link) error {
+			e.eventBus().publish("user."+user+".posts", Event{Link: post})
+			return han.UserPost(post)
+		},
+		func(comment *redditproto.Comment) error {
+			e.eventBus().publish("user."+user+".comments", Event{Comment: comment})
+			return han.UserComment(comment)
 		},
-		han.UserPost,
-		han.UserComment,
 		user,
+		e.logger(),
 	)
 	if err != nil {
 		return err
@@ -90,8 +124,9 @@ func (e *Engine) WatchUser(user string) error {
 	return nil
 }
 
-// Unwatch users stops monitoring a user.
-func (e *Engine) UnwatchUser(user string) error {
+// UnwatchUser stops monitoring a user. ctx is accepted for consistency with
+// WatchUser; unwatching does no I/O today.
+func (e *Engine) UnwatchUser(ctx context.Context, user string) error {
 	e.Lock()
 	defer e.Unlock()
 
@@ -103,17 +138,44 @@ func (e *Engine) UnwatchUser(user string) error {
 	return nil
 }
 
-// DigestThread returns a Link with a parsed comment tree.
-func (e *Engine) DigestThread(permalink string) (*redditproto.Link, error) {
-	return api.Thread(e.cli.Do, permalink)
+// DigestThread returns a Link with a parsed comment tree. ctx cancels the
+// underlying HTTP request.
+func (e *Engine) DigestThread(ctx context.Context, permalink string) (*redditproto.Link, error) {
+	return api.Thread(ctx, e.cli.Do, permalink)
 }
 
-// Stop stops the engine.
+// Stop begins a graceful shutdown of the engine: it cancels the context
+// RunContext is running under and returns immediately, without waiting for
+// any in-flight monitor update to finish. It is safe to call before Run or
+// RunContext has started, and safe to call more than once.
 func (e *Engine) Stop() {
-	e.stopSig <- true
+	e.Lock()
+	cancel := e.cancel
+	e.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
 }
 
+// Run runs the engine until Stop is called or an unrecoverable error
+// occurs.
 func (e *Engine) Run() error {
+	return e.RunContext(context.Background())
+}
+
+// RunContext runs the engine until ctx is cancelled, Stop is called, or an
+// unrecoverable error occurs. Cancelling ctx (directly, or via a
+// signal.Notify handler wired up by the caller) cancels any outstanding
+// HTTP request and lets the in-flight monitor update finish before
+// RunContext returns, so TearDown always runs over a fully quiesced engine.
+func (e *Engine) RunContext(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	e.Lock()
+	e.cancel = cancel
+	e.Unlock()
+	defer cancel()
+
 	if loader, ok := e.bot.(botfaces.Loader); ok {
 		if err := loader.SetUp(); err != nil {
 			return err
@@ -124,23 +186,35 @@ func (e *Engine) Run() error {
 		defer tearer.TearDown()
 	}
 
-	for !e.stop {
+	for {
 		select {
-		case <-e.stopSig:
-			e.stop = true
+		case <-ctx.Done():
+			e.logger().Info("engine stopped")
+			return nil
 		case <-time.After(e.blockTime()):
-			if err := e.updateMonitors(); err != nil {
-				if failer, ok := e.bot.(botfaces.Failer); !(ok && !failer.Fail(err)) {
+			for _, err := range e.updateMonitors(ctx) {
+				// A monitor reporting context.Canceled just means
+				// shutdown cancelled its in-flight request; that is
+				// not a failure worth reporting to Fail.
+				if errors.Is(err, context.Canceled) {
+					continue
+				}
+				e.logger().Error("monitor update failed", "err", err)
+				if failer, ok := e.bot.(botfaces.Failer); !ok || failer.Fail(err) {
 					return err
 				}
 			}
 		}
 	}
-
-	return nil
 }
 
-func (e *Engine) updateMonitors() error {
+// updateMonitors runs mon.Update for every monitor through a worker pool
+// bounded by e.concurrency(), so one slow monitor cannot stall the rest of
+// the tick. It always waits for every worker to finish (even if ctx is
+// cancelled mid-tick) before returning, so a single tick is never left
+// half-drained. It returns every error produced during the tick rather than
+// aborting on the first one.
+func (e *Engine) updateMonitors(ctx context.Context) multiError {
 	e.Lock()
 	monitors := []monitor.Monitor{}
 	for i := e.monitors.Front(); i != nil; i = i.Next() {
@@ -148,33 +222,91 @@ func (e *Engine) updateMonitors() error {
 	}
 	e.Unlock()
 
+	// scrape and exists close only over e.cli, which is safe to call
+	// from multiple goroutines, so they may be shared across workers.
+	scrape := func(ctx context.Context, path, tip string, limit int) (
+		[]*redditproto.Link,
+		[]*redditproto.Comment,
+		[]*redditproto.Message,
+		error,
+	) {
+		return api.Scrape(ctx, e.cli.Do, path, tip, limit)
+	}
+	exists := func(ctx context.Context, id string) (bool, error) {
+		return api.IsThereThing(ctx, e.cli.Do, id)
+	}
+
+	sem := make(chan struct{}, e.concurrency())
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs multiError
+
 	for _, mon := range monitors {
-		if err := mon.Update(
-			func(path, tip string, limit int) (
-				[]*redditproto.Link,
-				[]*redditproto.Comment,
-				[]*redditproto.Message,
-				error,
-			) {
-				return api.Scrape(e.cli.Do, path, tip, limit)
-			},
-			func(id string) (bool, error) {
-				return api.IsThereThing(e.cli.Do, id)
-			},
-		); err != nil {
-			return err
+		mon := mon
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			start := time.Now()
+			err := mon.Update(ctx, scrape, exists)
+			e.logger().Debug("monitor updated", "elapsed", time.Since(start), "err", err)
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// If the bot specifies a worker pool size by implementing
+// botfaces.Concurrency, return its value. Otherwise, fall back to
+// defaultConcurrency.
+func (e *Engine) concurrency() int {
+	if concurrency, ok := e.bot.(botfaces.Concurrency); ok {
+		if n := concurrency.Concurrency(); n > 0 {
+			return n
 		}
 	}
+	return defaultConcurrency
+}
 
-	return nil
+// RateLimit returns the rate-limit budget Reddit most recently reported, so
+// a bot can inspect it outside of a botfaces.RateLimitObserver callback. It
+// is the zero value until the engine has a client and that client has seen
+// a response.
+func (e *Engine) RateLimit() botfaces.RateLimit {
+	if e.cli == nil {
+		return botfaces.RateLimit{}
+	}
+	return e.cli.RateLimit()
 }
 
 // If the bot specifies a blockTime by implementing BlockTimer, return its
-// value. Otherwise, fall back to defaultBlockTime.
+// value. Otherwise, derive a wait from the current rate-limit budget so
+// requests are spread evenly across Reddit's reset window, falling back to
+// defaultBlockTime until the engine has a client or a budget has been
+// observed.
 func (e *Engine) blockTime() time.Duration {
 	if blockTimer, ok := e.bot.(botfaces.BlockTimer); ok {
 		return blockTimer.BlockTime()
-	} else {
+	}
+	if e.cli == nil {
 		return defaultBlockTime
 	}
+
+	limit := e.cli.RateLimit()
+	if observer, ok := e.bot.(botfaces.RateLimitObserver); ok {
+		observer.RateLimit(limit)
+	}
+	if wait := limit.BlockTime(); wait > 0 {
+		e.logger().Debug("rate limit backoff", "remaining", limit.Remaining, "reset", limit.Reset, "wait", wait)
+		return wait
+	}
+	return defaultBlockTime
 }
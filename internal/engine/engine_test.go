@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"container/list"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/turnage/graw/internal/monitor"
+)
+
+// slowMonitor sleeps for delay on every Update, to simulate a subreddit or
+// user timeline that is slow to scrape.
+type slowMonitor struct {
+	delay time.Duration
+}
+
+func (s *slowMonitor) Update(ctx context.Context, scrape monitor.Scraper, exists monitor.Exister) error {
+	time.Sleep(s.delay)
+	return nil
+}
+
+func newTestEngine(monitors ...monitor.Monitor) *Engine {
+	e := &Engine{
+		monitors: list.New(),
+	}
+	for _, mon := range monitors {
+		e.monitors.PushBack(mon)
+	}
+	return e
+}
+
+func TestUpdateMonitorsRunsInParallel(t *testing.T) {
+	const (
+		n     = 8
+		delay = 100 * time.Millisecond
+	)
+
+	monitors := make([]monitor.Monitor, n)
+	for i := range monitors {
+		monitors[i] = &slowMonitor{delay: delay}
+	}
+	e := newTestEngine(monitors...)
+
+	start := time.Now()
+	if errs := e.updateMonitors(context.Background()); len(errs) != 0 {
+		t.Fatalf("updateMonitors() returned errors: %v", errs)
+	}
+	elapsed := time.Since(start)
+
+	// If updateMonitors ran monitors sequentially, elapsed would be at
+	// least n*delay. With a worker pool of defaultConcurrency, it should
+	// finish in roughly ceil(n/defaultConcurrency)*delay.
+	max := time.Duration(n) * delay
+	if elapsed >= max {
+		t.Fatalf("updateMonitors took %v, expected well under the sequential worst case of %v", elapsed, max)
+	}
+}
+
+// erroringMonitor always fails its Update with err.
+type erroringMonitor struct {
+	err error
+}
+
+func (m *erroringMonitor) Update(ctx context.Context, scrape monitor.Scraper, exists monitor.Exister) error {
+	return m.err
+}
+
+func TestUpdateMonitorsCollectsAllErrors(t *testing.T) {
+	errA := &testError{"a"}
+	errB := &testError{"b"}
+	e := newTestEngine(&erroringMonitor{errA}, &erroringMonitor{errB}, &slowMonitor{})
+
+	errs := e.updateMonitors(context.Background())
+	if len(errs) != 2 {
+		t.Fatalf("updateMonitors() returned %d errors, want 2: %v", len(errs), errs)
+	}
+}
+
+type testError struct{ msg string }
+
+func (e *testError) Error() string { return e.msg }
@@ -0,0 +1,127 @@
+package engine
+
+import (
+	"sync"
+
+	"github.com/turnage/redditproto"
+)
+
+// subscriberBuffer is how many Events a subscriber's channel holds before
+// the bus starts dropping the oldest buffered event to make room for the
+// newest one.
+const subscriberBuffer = 16
+
+// CancelFunc unsubscribes a channel returned by Engine.Subscribe.
+type CancelFunc func()
+
+// Event carries a single thing a monitor produced, tagged with the topic it
+// was published to. Exactly one of Link, Comment, or Message is set.
+type Event struct {
+	Topic   string
+	Link    *redditproto.Link
+	Comment *redditproto.Comment
+	Message *redditproto.Message
+}
+
+// eventBus fans a monitor's output out to any number of subscribers,
+// grouped by topic (e.g. "user.<name>.posts"). It is purely additive: the
+// existing bot-callback dispatch (UserPost, UserComment, ...) keeps working
+// exactly as before, and is simply mirrored onto the bus as well.
+type eventBus struct {
+	mu      sync.Mutex
+	subs    map[string]map[chan Event]bool
+	dropped uint64
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{subs: map[string]map[chan Event]bool{}}
+}
+
+func (b *eventBus) subscribe(topic string) (<-chan Event, CancelFunc) {
+	ch := make(chan Event, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subs[topic] == nil {
+		b.subs[topic] = map[chan Event]bool{}
+	}
+	b.subs[topic][ch] = true
+	b.mu.Unlock()
+
+	cancelled := false
+	cancel := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if cancelled {
+			return
+		}
+		cancelled = true
+		delete(b.subs[topic], ch)
+		if len(b.subs[topic]) == 0 {
+			delete(b.subs, topic)
+		}
+		close(ch)
+	}
+	return ch, cancel
+}
+
+// publish delivers event to every subscriber of topic. It never blocks: a
+// subscriber that is not keeping up has its oldest buffered event dropped
+// to make room for event, rather than stalling the publisher.
+func (b *eventBus) publish(topic string, event Event) {
+	event.Topic = topic
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs[topic] {
+		select {
+		case ch <- event:
+		default:
+			select {
+			case <-ch:
+				b.dropped++
+			default:
+			}
+			select {
+			case ch <- event:
+			default:
+			}
+		}
+	}
+}
+
+func (b *eventBus) droppedEvents() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dropped
+}
+
+// eventBus lazily creates the Engine's event bus, so a zero-value Engine
+// (e.g. one built as a struct literal in tests) still works.
+func (e *Engine) eventBus() *eventBus {
+	e.Lock()
+	if e.events == nil {
+		e.events = newEventBus()
+	}
+	bus := e.events
+	e.Unlock()
+	return bus
+}
+
+// Subscribe returns a channel of Events published to topic, and a
+// CancelFunc that stops delivery and releases the channel. WatchUser
+// publishes to "user.<name>.posts" and "user.<name>.comments"; monitors for
+// other sources (subreddits, the inbox) will publish to their own
+// "posts.<subreddit>", "comments.<subreddit>", and "messages" topics as
+// those monitors gain bus support. Subscribing to a topic nothing
+// publishes to yet just yields an empty channel. The channel is buffered;
+// a subscriber that falls behind loses its oldest buffered event rather
+// than blocking the rest of the engine.
+func (e *Engine) Subscribe(topic string) (<-chan Event, CancelFunc) {
+	return e.eventBus().subscribe(topic)
+}
+
+// DroppedEvents returns the number of events dropped so far because a
+// subscriber's buffer was full.
+func (e *Engine) DroppedEvents() uint64 {
+	return e.eventBus().droppedEvents()
+}
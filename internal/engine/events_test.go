@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/turnage/redditproto"
+)
+
+func TestSubscribePublishDeliversToMatchingTopic(t *testing.T) {
+	e := newTestEngine()
+
+	ch, cancel := e.Subscribe("user.spez.posts")
+	defer cancel()
+
+	// A publish to a different topic must not be delivered here.
+	e.eventBus().publish("user.other.posts", Event{Link: &redditproto.Link{}})
+
+	want := &redditproto.Link{}
+	e.eventBus().publish("user.spez.posts", Event{Link: want})
+
+	select {
+	case got := <-ch:
+		if got.Topic != "user.spez.posts" || got.Link != want {
+			t.Fatalf("got %+v, want Link %+v on topic user.spez.posts", got, want)
+		}
+	default:
+		t.Fatal("subscriber channel had no event waiting")
+	}
+}
+
+func TestSubscribeDropsOldestWhenSubscriberFull(t *testing.T) {
+	e := newTestEngine()
+
+	ch, cancel := e.Subscribe("messages")
+	defer cancel()
+
+	for i := 0; i < subscriberBuffer+1; i++ {
+		e.eventBus().publish("messages", Event{})
+	}
+
+	if got := e.DroppedEvents(); got != 1 {
+		t.Fatalf("DroppedEvents() = %d, want 1", got)
+	}
+	if len(ch) != subscriberBuffer {
+		t.Fatalf("len(ch) = %d, want %d", len(ch), subscriberBuffer)
+	}
+}
+
+func TestCancelStopsDelivery(t *testing.T) {
+	e := newTestEngine()
+
+	ch, cancel := e.Subscribe("messages")
+	cancel()
+
+	if _, ok := <-ch; ok {
+		t.Fatal("channel should be closed after cancel")
+	}
+}
@@ -0,0 +1,48 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStopBeforeRunContextDoesNotBlock(t *testing.T) {
+	e := newTestEngine()
+
+	// Stop must be safe to call even before RunContext has set e.cancel.
+	done := make(chan struct{})
+	go func() {
+		e.Stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Stop() blocked with no RunContext running")
+	}
+}
+
+func TestRunContextReturnsAfterStop(t *testing.T) {
+	e := newTestEngine()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- e.RunContext(context.Background())
+	}()
+
+	// Give RunContext a moment to install its cancel func, then ask it to
+	// stop. If Stop still used a buffered channel send, this would race;
+	// with a context it is always observed on the next tick or select.
+	time.Sleep(10 * time.Millisecond)
+	e.Stop()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("RunContext() = %v, want nil after Stop()", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("RunContext() did not return after Stop()")
+	}
+}
@@ -0,0 +1,31 @@
+package engine
+
+// Logger receives structured diagnostic events from the engine and the
+// monitors and client it drives: scrape latencies, tip advances, dedupe
+// hits, rate-limit backoffs, and monitor errors. Each method takes a
+// message and an even number of arguments forming key-value pairs, in the
+// spirit of log15 and log/slog.
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+}
+
+// noopLogger discards everything logged to it. It is the Engine's default
+// Logger until WithLogger is used.
+type noopLogger struct{}
+
+func (noopLogger) Debug(string, ...interface{}) {}
+func (noopLogger) Info(string, ...interface{})  {}
+func (noopLogger) Warn(string, ...interface{})  {}
+func (noopLogger) Error(string, ...interface{}) {}
+
+// logger returns e's Logger, falling back to a no-op if none was
+// configured (e.g. e was built as a struct literal rather than via New).
+func (e *Engine) logger() Logger {
+	if e.log == nil {
+		return noopLogger{}
+	}
+	return e.log
+}
@@ -0,0 +1,33 @@
+package engine
+
+import "testing"
+
+// recordingLogger records every message logged to it, so tests can assert
+// on what the engine reported.
+type recordingLogger struct {
+	infos []string
+}
+
+func (l *recordingLogger) Debug(msg string, keyvals ...interface{}) {}
+func (l *recordingLogger) Info(msg string, keyvals ...interface{})  { l.infos = append(l.infos, msg) }
+func (l *recordingLogger) Warn(msg string, keyvals ...interface{})  {}
+func (l *recordingLogger) Error(msg string, keyvals ...interface{}) {}
+
+func TestEngineLogsNothingByDefault(t *testing.T) {
+	e := newTestEngine()
+	// logger() must not panic on a struct-literal Engine with no log set.
+	e.logger().Info("hello")
+	if _, ok := e.logger().(noopLogger); !ok {
+		t.Fatalf("logger() = %T, want noopLogger", e.logger())
+	}
+}
+
+func TestWithLoggerIsUsed(t *testing.T) {
+	log := &recordingLogger{}
+	e := New(nil, nil, WithLogger(log))
+
+	e.logger().Info("hello")
+	if len(log.infos) != 1 || log.infos[0] != "hello" {
+		t.Fatalf("logger received %v, want [\"hello\"]", log.infos)
+	}
+}
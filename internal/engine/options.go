@@ -0,0 +1,48 @@
+package engine
+
+import (
+	"container/list"
+
+	"github.com/turnage/graw/internal/client"
+)
+
+// EngineOption configures an Engine at construction time.
+type EngineOption func(*Engine)
+
+// WithLogger configures the engine, and the monitors it creates, to emit
+// structured logs to log. The engine's client is not given log and does not
+// log. Without this option, the engine logs nothing.
+func WithLogger(log Logger) EngineOption {
+	return func(e *Engine) {
+		e.log = log
+	}
+}
+
+// WithRetry wraps the engine's client so transient failures (network
+// errors, 5xx, 429, context.DeadlineExceeded) are retried with jittered
+// exponential backoff per policy, refreshing auth via authenticate on a
+// 401. When retries are exhausted, only the monitor update that triggered
+// them fails, and its error is handled like any other monitor error: the
+// engine keeps ticking if the bot implements botfaces.Failer and Fail
+// returns false, and stops otherwise.
+func WithRetry(policy client.RetryPolicy, authenticate client.Authenticator) EngineOption {
+	return func(e *Engine) {
+		e.cli = client.Retrying(e.cli, policy, authenticate)
+	}
+}
+
+// New builds an Engine that runs bot against Reddit using cli.
+func New(bot interface{}, cli client.Client, opts ...EngineOption) *Engine {
+	e := &Engine{
+		bot:          bot,
+		cli:          cli,
+		monitors:     list.New(),
+		userMonitors: map[string]*list.Element{},
+		events:       newEventBus(),
+		log:          noopLogger{},
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
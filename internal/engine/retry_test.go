@@ -0,0 +1,74 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/turnage/graw/internal/client"
+	"github.com/turnage/graw/internal/monitor"
+)
+
+// httpClient is a minimal client.Client backed by net/http, for exercising
+// WithRetry end to end against an httptest.Server.
+type httpClient struct {
+	http *http.Client
+	rl   client.RateLimitTracker
+}
+
+func (c *httpClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	return c.http.Do(req.WithContext(ctx))
+}
+
+func (c *httpClient) RateLimit() client.RateLimit {
+	return c.rl.Current()
+}
+
+// retryingMonitor issues one HTTP request per Update, failing if the
+// response is not a 200.
+type retryingMonitor struct {
+	cli client.Client
+	url string
+}
+
+func (m *retryingMonitor) Update(ctx context.Context, scrape monitor.Scraper, exists monitor.Exister) error {
+	req, err := http.NewRequest(http.MethodGet, m.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := m.cli.Do(ctx, req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+func TestUpdateMonitorsToleratesFlakyServerWithRetry(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	policy := client.RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+	cli := client.Retrying(&httpClient{http: srv.Client()}, policy, nil)
+
+	e := New(nil, cli)
+	e.monitors.PushBack(&retryingMonitor{cli: cli, url: srv.URL})
+
+	if errs := e.updateMonitors(context.Background()); len(errs) != 0 {
+		t.Fatalf("updateMonitors() = %v, want no errors once the client's own retries succeed", errs)
+	}
+}
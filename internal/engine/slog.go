@@ -0,0 +1,22 @@
+package engine
+
+import "log/slog"
+
+// SlogLogger adapts a *slog.Logger to the engine's Logger interface, so
+// bots get structured JSON logs for free from a JSON handler:
+//
+//	log := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+//	eng := engine.New(bot, cli, engine.WithLogger(engine.NewSlogLogger(log)))
+type SlogLogger struct {
+	log *slog.Logger
+}
+
+// NewSlogLogger wraps log as an engine.Logger.
+func NewSlogLogger(log *slog.Logger) SlogLogger {
+	return SlogLogger{log: log}
+}
+
+func (s SlogLogger) Debug(msg string, keyvals ...interface{}) { s.log.Debug(msg, keyvals...) }
+func (s SlogLogger) Info(msg string, keyvals ...interface{})  { s.log.Info(msg, keyvals...) }
+func (s SlogLogger) Warn(msg string, keyvals ...interface{})  { s.log.Warn(msg, keyvals...) }
+func (s SlogLogger) Error(msg string, keyvals ...interface{}) { s.log.Error(msg, keyvals...) }
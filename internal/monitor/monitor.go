@@ -0,0 +1,45 @@
+// Package monitor watches sources on Reddit (subreddits, users, the inbox)
+// for new things and reports them to a bot.
+package monitor
+
+import (
+	"context"
+
+	"github.com/turnage/redditproto"
+)
+
+// Scraper fetches things newer than tip from path, returning at most limit
+// of each kind. It must return promptly when ctx is cancelled.
+type Scraper func(ctx context.Context, path, tip string, limit int) (
+	[]*redditproto.Link,
+	[]*redditproto.Comment,
+	[]*redditproto.Message,
+	error,
+)
+
+// Exister reports whether Reddit still has a thing with the given id. It
+// must return promptly when ctx is cancelled.
+type Exister func(ctx context.Context, id string) (bool, error)
+
+// Logger receives structured diagnostics from a Monitor: each scrape's
+// latency, tip advances, and dedupe hits. It has the same shape as
+// engine.Logger, which monitor cannot import without creating an import
+// cycle; any engine.Logger satisfies this interface.
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+}
+
+// Monitor watches a single source for new things and dispatches them to a
+// bot. Implementations must tolerate Update being retried after a
+// transient error.
+type Monitor interface {
+	// Update scrapes for new things since the last call and reports them
+	// to the bot. ctx governs the whole call, including every scrape and
+	// exists invocation made during it. It must be safe to call
+	// concurrently with Update calls on other Monitors (but not with
+	// itself).
+	Update(ctx context.Context, scrape Scraper, exists Exister) error
+}